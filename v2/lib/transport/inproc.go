@@ -0,0 +1,24 @@
+// Go SDK for the KUSANAGI(tm) framework (http://kusanagi.io)
+// Copyright (c) 2016-2020 KUSANAGI S.L. All rights reserved.
+//
+// Distributed under the MIT license.
+//
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package transport
+
+import "fmt"
+
+// inprocTransport binds the request socket to an in-process endpoint. It is
+// mainly useful to exercise a component in tests without crossing any OS
+// transport.
+type inprocTransport struct{}
+
+func (inprocTransport) Address(opts Options) (string, error) {
+	name := opts.Socket
+	if name == "" {
+		name = fmt.Sprintf("%s-%s-%s", opts.Component, opts.Name, opts.Version)
+	}
+	return fmt.Sprintf("inproc://%s", name), nil
+}
@@ -0,0 +1,26 @@
+// Go SDK for the KUSANAGI(tm) framework (http://kusanagi.io)
+// Copyright (c) 2016-2020 KUSANAGI S.L. All rights reserved.
+//
+// Distributed under the MIT license.
+//
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package transport
+
+import (
+	"fmt"
+
+	"github.com/kusanagi/kusanagi-sdk-go/v2/lib"
+)
+
+// ipcTransport binds the request socket to a local IPC (UNIX domain) socket.
+type ipcTransport struct{}
+
+func (ipcTransport) Address(opts Options) (string, error) {
+	if opts.Socket != "" {
+		return fmt.Sprintf("ipc://%s", opts.Socket), nil
+	}
+	// Create a default name for the socket when no name is available.
+	return lib.IPC(opts.Component, opts.Name, opts.Version), nil
+}
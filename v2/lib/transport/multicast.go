@@ -0,0 +1,25 @@
+// Go SDK for the KUSANAGI(tm) framework (http://kusanagi.io)
+// Copyright (c) 2016-2020 KUSANAGI S.L. All rights reserved.
+//
+// Distributed under the MIT license.
+//
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package transport
+
+import "fmt"
+
+// multicastTransport exists to give PGM/EPGM a clear rejection instead of
+// the confusing ENOCOMPATPROTO bind error ZMQ itself would raise: multicast
+// is only compatible with PUB/SUB-family sockets, while the request socket
+// is a ROUTER, and request/reply framing fundamentally cannot run over a
+// one-way multicast stream. Selecting "pgm"/"epgm" for the request transport
+// is therefore always an error.
+type multicastTransport struct {
+	scheme string
+}
+
+func (t multicastTransport) Address(opts Options) (string, error) {
+	return "", fmt.Errorf("Transport %q cannot be used for request/response: multicast is one-way and only compatible with PUB/SUB sockets", t.scheme)
+}
@@ -0,0 +1,31 @@
+// Go SDK for the KUSANAGI(tm) framework (http://kusanagi.io)
+// Copyright (c) 2016-2020 KUSANAGI S.L. All rights reserved.
+//
+// Distributed under the MIT license.
+//
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package transport
+
+import "fmt"
+
+// defaultTCPHost is used when opts.Host is empty, preserving the historic
+// loopback-only default.
+const defaultTCPHost = "127.0.0.1"
+
+// tcpTransport binds the request socket to a TCP port, on the host given by
+// opts.Host (e.g. an interface address or "0.0.0.0" to accept remote
+// connections), defaulting to loopback-only when unset.
+type tcpTransport struct{}
+
+func (tcpTransport) Address(opts Options) (string, error) {
+	if opts.TCPPort <= 0 {
+		return "", fmt.Errorf("Invalid TCP port: %d", opts.TCPPort)
+	}
+	host := opts.Host
+	if host == "" {
+		host = defaultTCPHost
+	}
+	return fmt.Sprintf("tcp://%s:%d", host, opts.TCPPort), nil
+}
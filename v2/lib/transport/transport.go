@@ -0,0 +1,62 @@
+// Go SDK for the KUSANAGI(tm) framework (http://kusanagi.io)
+// Copyright (c) 2016-2020 KUSANAGI S.L. All rights reserved.
+//
+// Distributed under the MIT license.
+//
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+// Package transport builds the ZMQ bind address for the component request
+// socket, one implementation per supported network layer.
+package transport
+
+import "fmt"
+
+// Names of the transports bundled with the SDK, selectable via the
+// component's "--transport" CLI flag.
+const (
+	TCP    = "tcp"
+	IPC    = "ipc"
+	Inproc = "inproc"
+	PGM    = "pgm"
+	EPGM   = "epgm"
+)
+
+// Options carries the values needed to build a bind address. Only the
+// fields relevant to the selected Transport are read.
+type Options struct {
+	Component string
+	Name      string
+	Version   string
+	Socket    string
+	// Host is the interface the "tcp" transport binds to, e.g. "0.0.0.0" to
+	// accept remote connections. Defaults to loopback-only when empty.
+	Host           string
+	TCPPort        int
+	MulticastGroup string
+	Interface      string
+}
+
+// Transport builds the ZMQ bind address to use for the component's request
+// socket.
+type Transport interface {
+	Address(opts Options) (string, error)
+}
+
+// registry contains the transports bundled with the SDK, keyed by name.
+var registry = map[string]Transport{
+	TCP:    tcpTransport{},
+	IPC:    ipcTransport{},
+	Inproc: inprocTransport{},
+	PGM:    multicastTransport{scheme: PGM},
+	EPGM:   multicastTransport{scheme: EPGM},
+}
+
+// Get returns the registered Transport for name.
+func Get(name string) (Transport, error) {
+	t, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("Unknown transport: %q", name)
+	}
+	return t, nil
+}
@@ -11,8 +11,14 @@ package kusanagi
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -20,6 +26,7 @@ import (
 	"github.com/kusanagi/kusanagi-sdk-go/v2/lib/cli"
 	"github.com/kusanagi/kusanagi-sdk-go/v2/lib/log"
 	"github.com/kusanagi/kusanagi-sdk-go/v2/lib/payload"
+	"github.com/kusanagi/kusanagi-sdk-go/v2/lib/transport"
 	"github.com/pebbe/zmq4"
 )
 
@@ -59,6 +66,93 @@ func createErrorRespose(rid, message string) (responseMsg, error) {
 
 }
 
+// healthCheckAction is a sentinel action name handled directly by the
+// server, without dispatching to the component's user callbacks and ahead
+// of the worker pool's backpressure, so orchestrators (Kubernetes, Nomad,
+// ...) can probe component health without triggering user code and without
+// a merely-busy pool being mistaken for an unhealthy one.
+const healthCheckAction = "kusanagi.health"
+
+// healthStatus is the payload returned for the health-check sentinel action.
+type healthStatus struct {
+	Ready bool `json:"ready"`
+}
+
+// Create a response that reports whether the component is currently ready
+// to serve requests.
+func createHealthResponse(rid string, ready bool) (responseMsg, error) {
+	data, err := lib.Pack(healthStatus{Ready: ready})
+	if err != nil {
+		return nil, err
+	}
+	return responseMsg{[]byte(rid), emptyFrame, data}, nil
+}
+
+// Telemetry event published on the metrics socket for every processed
+// request. Subscribers can filter by component or action using the
+// "<component title>.<action>" topic prefix the event is published under.
+type telemetryEvent struct {
+	RequestID      string        `json:"request_id"`
+	Action         string        `json:"action"`
+	ComponentTitle string        `json:"component_title"`
+	Duration       time.Duration `json:"duration"`
+	Success        bool          `json:"success"`
+	Timeout        bool          `json:"timeout"`
+}
+
+// Publish a telemetry event for a processed request. The event is handed
+// off through s.telemetryc rather than written to the metrics socket
+// directly: a zmq4.Socket is not safe for concurrent use, and publishTelemetry
+// is called from every worker goroutine, so the socket is instead owned and
+// written to exclusively by the single goroutine started in server.start.
+// Does nothing when the metrics socket is disabled.
+func (s *server) publishTelemetry(st *state, duration time.Duration, success, timeout bool) {
+	if s.telemetryc == nil {
+		return
+	}
+
+	event := telemetryEvent{
+		RequestID:      st.id,
+		Action:         st.action,
+		ComponentTitle: st.componentTitle,
+		Duration:       duration,
+		Success:        success,
+		Timeout:        timeout,
+	}
+
+	select {
+	case s.telemetryc <- event:
+	default:
+		log.Warning("Dropping telemetry event: metrics channel is full")
+	}
+}
+
+// publishTelemetryLoop is the sole writer of the metrics socket: it reads
+// events published by the worker pool through s.telemetryc and publishes
+// them one at a time, until done is closed.
+func (s *server) publishTelemetryLoop(metrics *zmq4.Socket, done <-chan struct{}) {
+	for {
+		select {
+		case event := <-s.telemetryc:
+			data, err := lib.Pack(event)
+			if err != nil {
+				log.Errorf("Failed to pack telemetry event: %v", err)
+				continue
+			}
+
+			topic := fmt.Sprintf("%s.%s", event.ComponentTitle, event.Action)
+			if _, err := metrics.SendMessage(topic, data); err != nil {
+				if zmq4.AsErrno(err) == zmq4.ETERM {
+					return
+				}
+				log.Errorf("Failed to publish telemetry event: %v", err)
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
 // Cast the processor output results to interfaces.
 func pipeOutput(c <-chan requestOutput) <-chan interface{} {
 	pipe := make(chan interface{}, cap(c))
@@ -82,7 +176,7 @@ func newServer(c Component, p requestProcessor) (*server, error) {
 
 	// Setup the log level before the server is created
 	log.SetLevel(input.GetLogLevel())
-	return &server{c, input, p}, nil
+	return &server{component: c, input: input, processor: p}, nil
 }
 
 // SDK component server.
@@ -90,20 +184,281 @@ type server struct {
 	component Component
 	input     cli.Input
 	processor requestProcessor
+	// telemetryc is the channel workers publish telemetry events to. It is
+	// nil when the component was not started with a metrics address; the
+	// metrics socket itself is only ever touched by publishTelemetryLoop.
+	telemetryc chan telemetryEvent
+	// stats tracks worker pool usage for the lifetime of the server.
+	stats poolStats
+	// ready reports whether the component is currently ready to serve
+	// requests; 1 once listening, flipped back to 0 as soon as shutdown
+	// begins. Read and written atomically since it is probed from requests
+	// handled concurrently by the worker pool.
+	ready int32
+	// inflightWG tracks requests from the moment they are read off the
+	// socket until their response is produced (whether by a worker or by an
+	// immediate busy rejection), so a graceful shutdown can wait for
+	// everything already accepted to drain, not just what a worker started.
+	inflightWG sync.WaitGroup
+	// schemas holds the latest *payload.Mapping snapshot received from the
+	// framework. Updates always replace the pointer instead of mutating the
+	// mapping in place, so a snapshot read at dispatch time stays consistent
+	// for the lifetime of a request even if a newer one arrives mid-flight.
+	schemas atomic.Value
 }
 
-// Get the ZMQ channel address to use for listening incoming requests.
-func (s *server) getAddress() (address string) {
+// Schemas returns the latest schema mapping snapshot received from the
+// framework, or nil when none has been received yet.
+func (s *server) Schemas() *payload.Mapping {
+	v := s.schemas.Load()
+	if v == nil {
+		return nil
+	}
+	return v.(*payload.Mapping)
+}
+
+// poolStats tracks worker pool usage so behavior under overload is
+// observable: how many requests were handed to a worker, how many were
+// rejected because every worker was busy, and how many are in flight.
+type poolStats struct {
+	accepted int64
+	rejected int64
+	inFlight int64
+}
+
+func (p *poolStats) acceptedInc() { atomic.AddInt64(&p.accepted, 1) }
+func (p *poolStats) rejectedInc() { atomic.AddInt64(&p.rejected, 1) }
+func (p *poolStats) inFlightInc() { atomic.AddInt64(&p.inFlight, 1) }
+func (p *poolStats) inFlightDec() { atomic.AddInt64(&p.inFlight, -1) }
+
+// Snapshot returns the current accepted, rejected and in-flight request
+// counts.
+func (p *poolStats) Snapshot() (accepted, rejected, inFlight int64) {
+	return atomic.LoadInt64(&p.accepted), atomic.LoadInt64(&p.rejected), atomic.LoadInt64(&p.inFlight)
+}
+
+// Stats returns the current worker pool accepted, rejected and in-flight
+// request counts, so behavior under overload can be observed externally
+// (e.g. fed into a monitoring loop or logged periodically).
+func (s *server) Stats() (accepted, rejected, inFlight int64) {
+	return s.stats.Snapshot()
+}
+
+// Get the ZMQ channel address to use for publishing telemetry events, or an
+// empty string when the metrics socket is disabled.
+func (s *server) getMetricsAddress() string {
+	if s.input.IsMetricsTCPEnabled() {
+		return fmt.Sprintf("tcp://127.0.0.1:%d", s.input.GetMetricsTCP())
+	} else if name := s.input.GetMetricsSocket(); name != "" {
+		return fmt.Sprintf("ipc://%s", name)
+	}
+	return ""
+}
+
+// Get the name of the transport to use for listening incoming requests.
+func (s *server) getTransportName() string {
+	if name := s.input.GetTransport(); name != "" {
+		return name
+	}
+	// Preserve the historic default: TCP when enabled via "--tcp", IPC
+	// otherwise.
 	if s.input.IsTCPEnabled() {
-		address = fmt.Sprintf("tcp://127.0.0.1:%d", s.input.GetTCP())
-	} else if name := s.input.GetSocket(); name != "" {
-		address = fmt.Sprintf("ipc://%s", name)
-	} else {
-		// Create a default name for the socket when no name is available.
-		// The 'ipc://' prefix is removed from the string to get the socket name.
-		address = lib.IPC(s.input.GetComponent(), s.input.GetName(), s.input.GetVersion())
+		return transport.TCP
+	}
+	return transport.IPC
+}
+
+// Enable CurveZMQ authentication and encryption on socket, so the component
+// only accepts requests from authenticated framework peers. Only applies to
+// TCP transports; IPC, inproc and multicast transports keep the existing
+// unauthenticated behavior.
+//
+// The ZAP handler is per-context in pebbe/zmq4, and socket belongs to zctx,
+// so authentication must be started on zctx itself: starting it on the
+// package-global context would register a handler that socket's ZAP
+// requests never reach, leaving every CURVE handshake unanswered.
+func (s *server) enableCurveAuth(zctx *zmq4.Context, socket *zmq4.Socket) error {
+	secret, err := readCurveKey(s.input.GetCurveSecretKeyFile())
+	if err != nil {
+		return fmt.Errorf("Failed to read Curve secret key: %v", err)
+	}
+
+	keys, err := readCurveClientKeys(s.input.GetCurveClientKeysDir())
+	if err != nil {
+		return fmt.Errorf("Failed to read authorized client keys: %v", err)
+	}
+
+	if err := zctx.AuthStart(); err != nil {
+		return fmt.Errorf("Failed to start Curve authentication handler: %v", err)
+	}
+
+	domain := s.input.GetComponent()
+	zctx.AuthCurveAdd(domain, keys...)
+
+	if err := socket.ServerAuthCurve(domain, secret); err != nil {
+		return fmt.Errorf("Failed to enable Curve authentication on socket: %v", err)
+	}
+	return nil
+}
+
+// readCurveKey reads a single Z85-encoded CurveZMQ key from path.
+func readCurveKey(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// readCurveClientKeys reads one Z85-encoded public key per file in dir,
+// returning the keys of every authorized client.
+func readCurveClientKeys(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		key, err := readCurveKey(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Get the ZMQ channel address to use for listening incoming requests.
+func (s *server) getAddress() (string, error) {
+	t, err := transport.Get(s.getTransportName())
+	if err != nil {
+		return "", err
+	}
+
+	return t.Address(transport.Options{
+		Component:      s.input.GetComponent(),
+		Name:           s.input.GetName(),
+		Version:        s.input.GetVersion(),
+		Socket:         s.input.GetSocket(),
+		Host:           s.input.GetTCPHost(),
+		TCPPort:        s.input.GetTCP(),
+		MulticastGroup: s.input.GetMulticastGroup(),
+		Interface:      s.input.GetInterface(),
+	})
+}
+
+// job bundles a request message with the schema snapshot seen when it was
+// handed to the worker pool.
+type job struct {
+	msg     requestMsg
+	schemas *payload.Mapping
+}
+
+// Build a well-typed "server busy" response for a request rejected by the
+// worker pool because every worker is currently in flight.
+func (s *server) busyResponse(msg requestMsg) requestOutput {
+	rid := msg.getRequestID()
+	st := &state{id: rid, logger: log.NewRequestLogger(rid)}
+	return requestOutput{state: st, err: fmt.Errorf("Server busy, too many concurrent requests")}
+}
+
+// Answer the health-check sentinel action directly, without ever reaching
+// the worker pool: the listener calls this ahead of the backpressure select
+// in startMessageListener, so a saturated pool answers "busy" to real
+// requests but never to a liveness/readiness probe.
+func (s *server) healthResponse(msg requestMsg) requestOutput {
+	rid := msg.getRequestID()
+	st := &state{id: rid, logger: log.NewRequestLogger(rid)}
+	response, err := createHealthResponse(rid, atomic.LoadInt32(&s.ready) == 1)
+	if err != nil {
+		return requestOutput{state: st, err: err}
+	}
+	return requestOutput{state: st, response: response}
+}
+
+// Validate and process a single job, sending the result to resc.
+func (s *server) handleJob(ctx context.Context, j job, resc chan<- requestOutput, title string, timeout time.Duration) {
+	// Track how long the request takes to process, from dispatch to the
+	// final response or timeout, for telemetry purposes.
+	start := time.Now()
+
+	rid := j.msg.getRequestID()
+	action := j.msg.getAction()
+	logger := log.NewRequestLogger(rid)
+
+	// State for the request
+	state := state{
+		id:             rid,
+		componentTitle: title,
+		action:         action,
+		schemas:        j.schemas,
+		input:          s.input,
+		logger:         logger,
+	}
+
+	// Prepare defaults for the request output
+	output := requestOutput{state: &state}
+
+	// Check that the request action is defined
+	if c := s.component.(*component); !c.hasCallback(j.msg.getAction()) {
+		output.err = fmt.Errorf(`Invalid action for component %s: "%s"`, title, action)
+		s.publishTelemetry(&state, time.Since(start), false, false)
+		resc <- output
+		return
+
+	}
+
+	// Try to read the request payload when present
+	if v := j.msg.getPayload(); v != nil {
+		if err := lib.Unpack(v, state.command); err != nil {
+			log.Criticalf("Failed to read payload: %v", err)
+			output.err = fmt.Errorf(`Invalid payload for component %s: "%s"`, title, action)
+			s.publishTelemetry(&state, time.Since(start), false, false)
+			resc <- output
+			return
+		}
+	}
+
+	// Create a child context with the process execution timeout as limit
+	rctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	state.context = rctx
+
+	// Create a channel to wait for the processor output
+	outc := make(chan requestOutput)
+	defer close(outc)
+
+	// Process the request and return the response
+	go s.processor(s.component, &state, outc)
+
+	// Block until the processor finishes or the execution timeout is triggered
+	select {
+	case output := <-outc:
+		s.publishTelemetry(&state, time.Since(start), output.err == nil, false)
+		resc <- output
+	case <-rctx.Done():
+		logger.Warningf("Execution timed out after %dms. PID: %d", timeout, os.Getpid())
+		s.publishTelemetry(&state, time.Since(start), false, true)
+	}
+}
+
+// Run a worker that processes jobs from jobs until the channel is closed.
+// s.inflightWG is counted down here, but it is counted up as soon as a
+// message is read off the wire (see server.start), not when a worker picks
+// it up, so a graceful shutdown also waits for requests queued in msgc that
+// have not reached a worker yet.
+func (s *server) runWorker(ctx context.Context, jobs <-chan job, resc chan<- requestOutput, wg *sync.WaitGroup, title string, timeout time.Duration) {
+	defer wg.Done()
+	for j := range jobs {
+		s.stats.inFlightInc()
+		s.handleJob(ctx, j, resc, title, timeout)
+		s.stats.inFlightDec()
+		s.inflightWG.Done()
 	}
-	return address
 }
 
 func (s *server) startMessageListener(msgc <-chan requestMsg) <-chan requestOutput {
@@ -113,17 +468,35 @@ func (s *server) startMessageListener(msgc <-chan requestMsg) <-chan requestOutp
 	// Get the title to use for the component
 	title := s.input.GetComponentTitle()
 
-	// Handle messages until the messages channel is closed
-	go func() {
-		// TODO: See how to avoid race conditions when mapping are updated here (and read by userland)
-		var schemas *payload.Mapping
+	// Process execution timeout
+	timeout := time.Duration(s.input.GetTimeout()) * time.Millisecond
 
-		// Process execution timeout
-		timeout := time.Duration(s.input.GetTimeout()) * time.Millisecond
+	// Define a parent context for each request
+	ctx := context.Background()
 
-		// Define a parent context for each request
-		ctx := context.Background()
+	// Size the bounded worker pool, defaulting to GOMAXPROCS when unset.
+	workers := s.input.GetWorkers()
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	// Jobs channel feeding the worker pool, buffered to the pool size: an
+	// unbuffered channel only accepts a send when a worker happens to be
+	// parked on the receive at that exact instant, so requests arriving
+	// while every worker is merely between jobs (not actually overloaded)
+	// would be rejected as busy well below capacity. A full buffer of this
+	// size means every worker is genuinely occupied, which is what the
+	// select below uses to detect real saturation.
+	jobs := make(chan job, workers)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go s.runWorker(ctx, jobs, resc, &wg, title, timeout)
+	}
 
+	// Handle messages until the messages channel is closed
+	go func() {
 		for {
 			// Block until a request message is received
 			msg, closed := <-msgc
@@ -135,78 +508,91 @@ func (s *server) startMessageListener(msgc <-chan requestMsg) <-chan requestOutp
 			// Check that the multipart message is valid
 			if err := msg.check(); err != nil {
 				log.Critical(err)
+				// The message's lifecycle ends here: it will never reach a
+				// worker, so stop counting it as in flight.
+				s.inflightWG.Done()
 				// Log the error and continue listening for incoming requests
 				continue
 			}
 
-			// Try to read the new schemas when present
+			// Try to read the new schemas when present. The mapping is
+			// treated as an immutable snapshot: a full replace is stored
+			// atomically, never mutated in place, so in-flight requests
+			// holding an older snapshot are unaffected.
 			if v := msg.getSchemas(); v != nil {
-				if err := lib.Unpack(v, &schemas); err != nil {
+				var mapping *payload.Mapping
+				if err := lib.Unpack(v, &mapping); err != nil {
 					log.Errorf("Failed to read schemas: %v", err)
+				} else {
+					s.schemas.Store(mapping)
 				}
 			}
 
-			// Process the request message in a new goroutine
-			// TODO: Move to a function
-			go func() {
-				rid := msg.getRequestID()
-				action := msg.getAction()
-				logger := log.NewRequestLogger(rid)
-
-				// State for the request
-				state := state{
-					id:      rid,
-					action:  action,
-					schemas: schemas,
-					input:   s.input,
-					logger:  logger,
-				}
-
-				// Prepare defaults for the request output
-				output := requestOutput{state: &state}
-
-				// Check that the request action is defined
-				if c := s.component.(*component); !c.hasCallback(msg.getAction()) {
-					output.err = fmt.Errorf(`Invalid action for component %s: "%s"`, title, action)
-					resc <- output
-					return
+			// Answer the health-check sentinel ahead of the worker-pool
+			// backpressure select, so a saturated pool never turns a
+			// liveness/readiness probe into a "server busy" error.
+			if msg.getAction() == healthCheckAction {
+				resc <- s.healthResponse(msg)
+				s.inflightWG.Done()
+				continue
+			}
 
-				}
+			// Hand the request off to the worker pool. When every worker is
+			// busy, apply backpressure by responding immediately with a
+			// "server busy" error instead of growing an unbounded number of
+			// goroutines.
+			select {
+			case jobs <- job{msg, s.Schemas()}:
+				s.stats.acceptedInc()
+			default:
+				s.stats.rejectedInc()
+				accepted, rejected, inFlight := s.Stats()
+				log.Warningf("Rejecting request %q: worker pool busy (accepted: %d, rejected: %d, in-flight: %d)",
+					msg.getRequestID(), accepted, rejected, inFlight)
+				resc <- s.busyResponse(msg)
+				// Answered immediately: this message's lifecycle ends here.
+				s.inflightWG.Done()
+			}
+		}
 
-				// Try to read the new schemas when present
-				if v := msg.getPayload(); v != nil {
-					if err := lib.Unpack(v, state.command); err != nil {
-						log.Criticalf("Failed to read payload: %v", err)
-						output.err = fmt.Errorf(`Invalid payload for component %s: "%s"`, title, action)
-						resc <- output
-						return
-					}
-				}
+		close(jobs)
+		wg.Wait()
+	}()
 
-				// Create a child context with the process execution timeout as limit
-				ctx, cancel := context.WithTimeout(ctx, timeout)
-				defer cancel()
-				state.context = ctx
+	return resc
+}
 
-				// Create a channel to wait for the processor output
-				outc := make(chan requestOutput)
-				defer close(outc)
+// respondWhileDraining answers a request that arrived after shutdown began,
+// directly from the reactor goroutine instead of through msgc/the worker
+// pool. A health-check probe gets a real {ready:false} answer; every other
+// action is turned away immediately rather than being queued for a pool
+// that is being drained, not grown.
+func (s *server) respondWhileDraining(socket *zmq4.Socket, msg requestMsg) error {
+	if err := msg.check(); err != nil {
+		log.Critical(err)
+		return nil
+	}
 
-				// Process the request and return the response
-				go s.processor(s.component, &state, outc)
+	rid := msg.getRequestID()
+	var response responseMsg
+	var err error
+	if msg.getAction() == healthCheckAction {
+		response, err = createHealthResponse(rid, false)
+	} else {
+		response, err = createErrorRespose(rid, "Server is shutting down")
+	}
+	if err != nil {
+		log.Errorf("Failed to create response while draining: %v", err)
+		return nil
+	}
 
-				// Block until the processor finishes or the execution timeout is triggered
-				select {
-				case output := <-outc:
-					resc <- output
-				case <-ctx.Done():
-					logger.Warningf("Execution timed out after %dms. PID: %d", timeout, os.Getpid())
-				}
-			}()
+	if _, err := socket.SendMessage(response); err != nil {
+		if zmq4.AsErrno(err) == zmq4.ETERM {
+			return err
 		}
-	}()
-
-	return resc
+		log.Errorf("Failed to send response to client: %v", err)
+	}
+	return nil
 }
 
 func (s *server) start() error {
@@ -216,25 +602,6 @@ func (s *server) start() error {
 		return err
 	}
 
-	// Listen for termination signals
-	go func() {
-		// Define a channel to receive system signals
-		sigc := make(chan os.Signal, 1)
-		signal.Notify(sigc, syscall.SIGHUP, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
-		// Block until a signal is received
-		<-sigc
-		log.Debug("Termination signal received")
-		// Terminate the ZMQ context to close sockets gracefully
-		if err := zctx.Term(); err != nil {
-			log.Errorf("Failed to terminate sockets context: %v", err)
-		} else {
-			log.Debug("Socket context terminated successfully")
-		}
-		// Clear the default ZMQ settings for retrying operations after EINTR.
-		zmq4.SetRetryAfterEINTR(false)
-		zctx.SetRetryAfterEINTR(false)
-	}()
-
 	// Create a socket to receive incoming requests
 	socket, err := zctx.NewSocket(zmq4.ROUTER)
 	if err != nil {
@@ -252,14 +619,53 @@ func (s *server) start() error {
 		return fmt.Errorf("Failed to set socket's high water mark option: %v", err)
 	}
 
+	// Enable CurveZMQ authentication when requested on a TCP transport.
+	// IPC and inproc sockets never leave the host, and multicast transports
+	// do not support CURVE, so they keep the existing unauthenticated
+	// behavior.
+	if s.getTransportName() == transport.TCP && s.input.IsCurveEnabled() {
+		if err := s.enableCurveAuth(zctx, socket); err != nil {
+			return fmt.Errorf("Failed to configure Curve authentication: %v", err)
+		}
+		defer zctx.AuthStop()
+	}
+
 	// Start listening for incoming requests
-	address := s.getAddress()
+	address, err := s.getAddress()
+	if err != nil {
+		return fmt.Errorf("Failed to resolve transport address: %v", err)
+	}
 	log.Debugf(`Listening for request at address: "%s"`, address)
 	if err := socket.Bind(address); err != nil {
 		return fmt.Errorf(`Faled to open socket at address "%s": %v`, address, err)
 	}
 	defer socket.Unbind(address)
 
+	// Optionally start a PUB socket to publish per-request telemetry, so
+	// operators can feed a Prometheus-style exporter or dashboard without
+	// having to parse the request logger output.
+	if metricsAddress := s.getMetricsAddress(); metricsAddress != "" {
+		metrics, err := zctx.NewSocket(zmq4.PUB)
+		if err != nil {
+			return fmt.Errorf("Failed to create metrics socket: %v", err)
+		}
+		defer metrics.Close()
+
+		if err := metrics.Bind(metricsAddress); err != nil {
+			return fmt.Errorf(`Faled to open metrics socket at address "%s": %v`, metricsAddress, err)
+		}
+		defer metrics.Unbind(metricsAddress)
+
+		log.Debugf(`Publishing telemetry at address: "%s"`, metricsAddress)
+
+		// The metrics socket is written exclusively by this goroutine; the
+		// worker pool only ever sends events through s.telemetryc.
+		s.telemetryc = make(chan telemetryEvent, 1000)
+		telemetryDone := make(chan struct{})
+		defer close(telemetryDone)
+		go s.publishTelemetryLoop(metrics, telemetryDone)
+	}
+
 	// Create a buffered channel to send request payloads to the message listener.
 	// The channel is buffered to allow faster request processing by the reactor.
 	msgc := make(chan requestMsg, 1000)
@@ -287,6 +693,22 @@ func (s *server) start() error {
 				log.Errorf("Failed to read request payload: %v", err)
 			}
 		}
+		// Once shutdown begins, stop admitting new requests: Add with a
+		// positive delta races s.inflightWG.Wait below once the counter may
+		// already be zero, and accepting fresh work for the whole grace
+		// period would mean the drain never completes. Answer directly from
+		// here instead, on the same reactor goroutine that owns socket
+		// writes via the resc handler below, so health probes still get an
+		// observable "not ready" and everything else is turned away rather
+		// than queued for a pool that is draining, not growing.
+		if atomic.LoadInt32(&s.ready) == 0 {
+			return s.respondWhileDraining(socket, msg)
+		}
+
+		// Count the message as in flight as soon as it is read off the
+		// wire, not only once a worker picks it up: msgc is buffered, so a
+		// graceful shutdown must also wait for messages queued there.
+		s.inflightWG.Add(1)
 		msgc <- msg
 		return nil
 	})
@@ -316,6 +738,59 @@ func (s *server) start() error {
 		}
 		return nil
 	})
+
+	// The component is ready to serve requests once it starts reacting to
+	// incoming messages.
+	atomic.StoreInt32(&s.ready, 1)
+
+	// Listen for termination signals and perform a two-phase shutdown: flip
+	// readiness off (still observable via the health-check sentinel action),
+	// drain in-flight requests up to a grace period, then terminate the ZMQ
+	// context.
+	go func() {
+		// Define a channel to receive system signals
+		sigc := make(chan os.Signal, 1)
+		signal.Notify(sigc, syscall.SIGHUP, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
+		// Block until a signal is received
+		<-sigc
+		log.Debug("Termination signal received")
+
+		// Flip readiness off immediately so orchestrators stop routing new
+		// traffic to this component while it drains in-flight requests.
+		atomic.StoreInt32(&s.ready, 0)
+
+		// Deliberately keep the request socket registered with the reactor
+		// during the drain window, instead of calling reactor.RemoveSocket:
+		// the reactor's socket map is only safe to mutate from its own
+		// goroutine (the one running reactor.Run below), and removing the
+		// socket here would also stop the kusanagi.health sentinel action
+		// from ever being read, making the "not ready" state unobservable
+		// by probes. zctx.Term below is what finally stops the reactor.
+		drained := make(chan struct{})
+		go func() {
+			s.inflightWG.Wait()
+			close(drained)
+		}()
+
+		grace := time.Duration(s.input.GetShutdownTimeout()) * time.Millisecond
+		select {
+		case <-drained:
+			log.Debug("All in-flight requests drained")
+		case <-time.After(grace):
+			log.Warningf("Shutdown grace period of %s elapsed with requests still in flight", grace)
+		}
+
+		// Terminate the ZMQ context to close sockets gracefully
+		if err := zctx.Term(); err != nil {
+			log.Errorf("Failed to terminate sockets context: %v", err)
+		} else {
+			log.Debug("Socket context terminated successfully")
+		}
+		// Clear the default ZMQ settings for retrying operations after EINTR.
+		zmq4.SetRetryAfterEINTR(false)
+		zctx.SetRetryAfterEINTR(false)
+	}()
+
 	reactor.Run(time.Second)
 	log.Info("Component stopped")
 	return nil
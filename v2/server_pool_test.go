@@ -0,0 +1,131 @@
+// Go SDK for the KUSANAGI(tm) framework (http://kusanagi.io)
+// Copyright (c) 2016-2020 KUSANAGI S.L. All rights reserved.
+//
+// Distributed under the MIT license.
+//
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package kusanagi
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestPoolStatsSnapshot verifies that accepted, rejected and in-flight
+// counters are tracked independently and can be read back, so the worker
+// pool's behavior under overload is observable rather than write-only.
+func TestPoolStatsSnapshot(t *testing.T) {
+	var stats poolStats
+
+	stats.acceptedInc()
+	stats.acceptedInc()
+	stats.rejectedInc()
+	stats.inFlightInc()
+	stats.inFlightInc()
+	stats.inFlightDec()
+
+	accepted, rejected, inFlight := stats.Snapshot()
+	if accepted != 2 {
+		t.Errorf("expected 2 accepted requests, got %d", accepted)
+	}
+	if rejected != 1 {
+		t.Errorf("expected 1 rejected request, got %d", rejected)
+	}
+	if inFlight != 1 {
+		t.Errorf("expected 1 in-flight request, got %d", inFlight)
+	}
+}
+
+// TestServerStats verifies that server.Stats exposes the same counters as
+// the underlying poolStats, since that accessor is what external callers
+// (and the "worker pool busy" log line) read.
+func TestServerStats(t *testing.T) {
+	s := &server{}
+	s.stats.acceptedInc()
+	s.stats.rejectedInc()
+	s.stats.rejectedInc()
+	s.stats.inFlightInc()
+
+	accepted, rejected, inFlight := s.Stats()
+	if accepted != 1 || rejected != 2 || inFlight != 1 {
+		t.Fatalf("expected (1, 2, 1), got (%d, %d, %d)", accepted, rejected, inFlight)
+	}
+}
+
+// TestBoundedWorkerPoolLimitsConcurrency reproduces the job-dispatch pattern
+// used by startMessageListener: a fixed number of long-lived workers read
+// from a jobs channel buffered to the pool size, and a caller that cannot
+// send because that buffer is full rejects instead of spawning a new
+// goroutine per item. It
+// proves that a burst of slow jobs, including ones slower than the pool can
+// drain, cannot grow the number of goroutines processing them past the
+// configured pool size, and that every submitted job is accounted for as
+// either accepted or rejected.
+func TestBoundedWorkerPoolLimitsConcurrency(t *testing.T) {
+	const workers = 4
+	const submitted = 200
+
+	jobc := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for range jobc {
+				mu.Lock()
+				inFlight++
+				if inFlight > maxInFlight {
+					maxInFlight = inFlight
+				}
+				mu.Unlock()
+
+				// Simulate a slow action that would otherwise exhaust
+				// memory if every job spawned its own goroutine.
+				time.Sleep(time.Millisecond)
+
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+			}
+		}()
+	}
+
+	goroutinesBefore := runtime.NumGoroutine()
+
+	var stats poolStats
+	for i := 0; i < submitted; i++ {
+		select {
+		case jobc <- struct{}{}:
+			stats.acceptedInc()
+		default:
+			stats.rejectedInc()
+		}
+	}
+	close(jobc)
+	wg.Wait()
+
+	if maxInFlight > workers {
+		t.Fatalf("expected at most %d concurrent jobs, observed %d", workers, maxInFlight)
+	}
+
+	accepted, rejected, _ := stats.Snapshot()
+	if accepted+rejected != submitted {
+		t.Fatalf("expected %d jobs accounted for, got %d accepted + %d rejected", submitted, accepted, rejected)
+	}
+
+	// The pool must not have grown one goroutine per submitted job: a small
+	// constant number of extra goroutines (test runtime, GC, ...) is fine,
+	// hundreds of leaked job goroutines is the regression this guards
+	// against.
+	if n := runtime.NumGoroutine(); n > goroutinesBefore+workers+10 {
+		t.Fatalf("goroutine count grew unexpectedly: started at %d, now %d", goroutinesBefore, n)
+	}
+}
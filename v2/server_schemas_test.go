@@ -0,0 +1,71 @@
+// Go SDK for the KUSANAGI(tm) framework (http://kusanagi.io)
+// Copyright (c) 2016-2020 KUSANAGI S.L. All rights reserved.
+//
+// Distributed under the MIT license.
+//
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package kusanagi
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/kusanagi/kusanagi-sdk-go/v2/lib/payload"
+)
+
+// TestSchemasConcurrentUpdateAndRead stresses concurrent schema snapshot
+// replacements against concurrent reads, simulating schema frames arriving
+// on the listener goroutine while requests dispatched to the worker pool
+// read the snapshot captured at their own dispatch time. Run with
+// `go test -race` to confirm the copy-on-write atomic.Value storage removed
+// the race the bare *payload.Mapping pointer used to have.
+func TestSchemasConcurrentUpdateAndRead(t *testing.T) {
+	s := &server{}
+
+	var wg sync.WaitGroup
+
+	// Writer: simulate new schema frames replacing the snapshot, the way
+	// startMessageListener does on every getSchemas() frame.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			s.schemas.Store(&payload.Mapping{})
+		}
+	}()
+
+	// Readers: simulate in-flight requests capturing a snapshot through
+	// Schemas() at dispatch time and holding onto it for the request's
+	// duration.
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				_ = s.Schemas()
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// TestSchemasNilUntilFirstUpdate verifies Schemas() reports no snapshot
+// until the first schema frame is stored, rather than panicking on the
+// atomic.Value zero value.
+func TestSchemasNilUntilFirstUpdate(t *testing.T) {
+	s := &server{}
+
+	if m := s.Schemas(); m != nil {
+		t.Fatalf("expected no schema snapshot before the first update, got %v", m)
+	}
+
+	mapping := &payload.Mapping{}
+	s.schemas.Store(mapping)
+
+	if m := s.Schemas(); m != mapping {
+		t.Fatalf("expected the stored snapshot to be returned, got %v", m)
+	}
+}